@@ -0,0 +1,357 @@
+// Package concurrentskiplist implements a thread-safe skip list suitable
+// for use as an LSM-tree memtable.
+//
+// Unlike the skiplist package, nodes are not individually heap-allocated
+// *node pointers. Instead they are packed into a single preallocated byte
+// arena and addressed by uint32 offsets into that arena, following the
+// design used by Badger and Pebble's arenaskl. Forward links are stored as
+// atomic.Uint32 offsets, and Set (Put) splices new nodes into each level
+// with a compare-and-swap, retrying the splice search on collision. This
+// lets many goroutines insert concurrently without a global lock.
+//
+// Deletion is not a first-class operation; callers wanting LSM-style
+// tombstones should Put a sentinel value for the key.
+package concurrentskiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"sync/atomic"
+	"unsafe"
+)
+
+// p is the fraction of nodes at height h that also reach height h+1,
+// matching the geometric distribution used by skiplist.SkipList.
+const p = 0.25
+
+// maxHeight bounds the number of forward pointers any node can have.
+const maxHeight = 20
+
+// arena is a preallocated byte buffer that nodes are packed into. Offsets
+// into the arena are used instead of pointers so that node allocation
+// never needs per-node heap bookkeeping and concurrent readers can follow
+// offsets without synchronizing with the Go allocator.
+type arena struct {
+	buf []byte
+	n   atomic.Uint32
+}
+
+// newArena allocates a new arena with the given capacity in bytes.
+func newArena(size int) *arena {
+	a := &arena{buf: make([]byte, size)}
+	// Reserve offset 0 so it can be used as a nil sentinel.
+	a.n.Store(1)
+	return a
+}
+
+// alloc reserves size bytes aligned to align (a power of two) and returns
+// the offset of the start of the reservation. It panics if the arena is
+// exhausted, mirroring the fixed-capacity contract of arenaskl-style
+// arenas.
+func (a *arena) alloc(size, align uint32) uint32 {
+	padded := size + align - 1
+	newOffset := a.n.Add(padded)
+	if int(newOffset) > len(a.buf) {
+		panic("concurrentskiplist: arena out of space")
+	}
+	offset := (newOffset - size) &^ (align - 1)
+	return offset
+}
+
+// node is a handle onto a node packed into an Arena. It carries no state
+// of its own beyond the offset, so it is cheap to pass around and safe to
+// read concurrently with writers (a node's key and value are never
+// rewritten once packed, and writers change tower slots, including those
+// of nodes already in the list, only through atomic stores and CAS).
+type node struct {
+	arena  *arena
+	offset uint32
+}
+
+func (n node) isNil() bool {
+	return n.offset == 0
+}
+
+// Node layout within the arena, all fields little-endian:
+//
+//	height  uint32
+//	keySize uint32
+//	key     [keySize]byte
+//	valSize uint32
+//	val     [valSize]byte
+//	tower   [height]uint32 (atomic)
+func (n node) height() uint32 {
+	return binary.LittleEndian.Uint32(n.arena.buf[n.offset:])
+}
+
+func (n node) keySize() uint32 {
+	return binary.LittleEndian.Uint32(n.arena.buf[n.offset+4:])
+}
+
+func (n node) key() []byte {
+	start := n.offset + 8
+	return n.arena.buf[start : start+n.keySize()]
+}
+
+func (n node) valSizeOffset() uint32 {
+	return n.offset + 8 + n.keySize()
+}
+
+func (n node) valSize() uint32 {
+	return binary.LittleEndian.Uint32(n.arena.buf[n.valSizeOffset():])
+}
+
+func (n node) value() []byte {
+	start := n.valSizeOffset() + 4
+	return n.arena.buf[start : start+n.valSize()]
+}
+
+func (n node) towerOffset(level int) uint32 {
+	return n.valSizeOffset() + 4 + n.valSize() + uint32(level)*4
+}
+
+func (n node) loadNext(level int) uint32 {
+	slot := (*uint32)(unsafe.Pointer(&n.arena.buf[n.towerOffset(level)]))
+	return atomic.LoadUint32(slot)
+}
+
+func (n node) storeNext(level int, offset uint32) {
+	slot := (*uint32)(unsafe.Pointer(&n.arena.buf[n.towerOffset(level)]))
+	atomic.StoreUint32(slot, offset)
+}
+
+func (n node) casNext(level int, old, new uint32) bool {
+	slot := (*uint32)(unsafe.Pointer(&n.arena.buf[n.towerOffset(level)]))
+	return atomic.CompareAndSwapUint32(slot, old, new)
+}
+
+// newNode packs a node with the given key, value and tower height into
+// the arena and returns its offset. Only height uint32 tower slots are
+// reserved, so short nodes (the common case, since P(height >= h) decays
+// geometrically) cost little arena space.
+func (a *arena) newNode(key, val []byte, height int) uint32 {
+	size := 4 + 4 + len(key) + 4 + len(val) + height*4
+	offset := a.alloc(uint32(size), 4)
+
+	binary.LittleEndian.PutUint32(a.buf[offset:], uint32(height))
+	binary.LittleEndian.PutUint32(a.buf[offset+4:], uint32(len(key)))
+	copy(a.buf[offset+8:], key)
+
+	valSizeOffset := offset + 8 + uint32(len(key))
+	binary.LittleEndian.PutUint32(a.buf[valSizeOffset:], uint32(len(val)))
+	copy(a.buf[valSizeOffset+4:], val)
+
+	return offset
+}
+
+// SkipList is a concurrent, lock-free skip list backed by an Arena. Keys
+// are ordered with bytes.Compare. The zero value is not usable; create
+// one with NewArena.
+type SkipList struct {
+	arena  *arena
+	head   uint32
+	height atomic.Int32
+}
+
+// NewArena returns an empty SkipList backed by a new preallocated byte
+// arena of the given capacity. Put panics once the arena is exhausted.
+func NewArena(arenaSize int) *SkipList {
+	a := newArena(arenaSize)
+	head := a.newNode(nil, nil, maxHeight)
+	s := &SkipList{arena: a, head: head}
+	s.height.Store(1)
+	return s
+}
+
+func (s *SkipList) headNode() node {
+	return node{s.arena, s.head}
+}
+
+// randomHeight picks a tower height using the same p=0.25 geometric
+// distribution as skiplist.SkipList.
+func randomHeight() int {
+	h := 1
+	for h < maxHeight && rand.Float64() < p {
+		h++
+	}
+	return h
+}
+
+// findSpliceForLevel walks level from start and returns the node
+// immediately before key (prev) and immediately after-or-equal to key
+// (next) at that level.
+func (s *SkipList) findSpliceForLevel(key []byte, level int, start node) (prev, next node) {
+	prev = start
+	for {
+		nextOffset := prev.loadNext(level)
+		if nextOffset == 0 {
+			return prev, node{s.arena, 0}
+		}
+		next = node{s.arena, nextOffset}
+		if bytes.Compare(next.key(), key) >= 0 {
+			return prev, next
+		}
+		prev = next
+	}
+}
+
+// findSplice returns, for every level from the list's current top level
+// down to 0, the node immediately before key (prevs[level]) and the node
+// immediately at-or-after key (nexts[level]). It performs a single
+// top-down descent: a node with a tower reaching level L also has a
+// tower slot at every level below L, so the prev found at level L is
+// still a valid starting point for the search at level L-1 and the scan
+// only needs to cover the gap from there, instead of restarting from the
+// head at every level.
+func (s *SkipList) findSplice(key []byte) (prevs, nexts [maxHeight]node) {
+	prev := s.headNode()
+	for level := int(s.height.Load()) - 1; level >= 0; level-- {
+		prev, nexts[level] = s.findSpliceForLevel(key, level, prev)
+		prevs[level] = prev
+	}
+	return prevs, nexts
+}
+
+// findGreaterOrEqual returns the first node whose key is >= key, or a nil
+// node if none exists.
+func (s *SkipList) findGreaterOrEqual(key []byte) node {
+	current := s.headNode()
+	for level := int(s.height.Load()) - 1; level >= 0; level-- {
+		for {
+			nextOffset := current.loadNext(level)
+			if nextOffset == 0 {
+				break
+			}
+			next := node{s.arena, nextOffset}
+			if bytes.Compare(next.key(), key) >= 0 {
+				break
+			}
+			current = next
+		}
+	}
+	return node{s.arena, current.loadNext(0)}
+}
+
+// Get returns the value associated with key, if present.
+func (s *SkipList) Get(key []byte) ([]byte, bool) {
+	n := s.findGreaterOrEqual(key)
+	if n.isNil() || !bytes.Equal(n.key(), key) {
+		return nil, false
+	}
+	return n.value(), true
+}
+
+// Put inserts key/val, overwriting any existing node for key. The new
+// node is spliced into each of its tower levels with a CAS, starting
+// from the single top-down findSplice descent; a level whose CAS loses
+// a race to a concurrent writer is retried by re-scanning just the gap
+// at that level from the already-known prev, not by restarting the
+// whole descent from the head.
+//
+// Whichever Put for key succeeds in splicing its node in first becomes
+// the front of a (possibly growing, under concurrent Puts for the same
+// key) run of same-key nodes; every Put, immediately after splicing
+// itself in, walks forward from its own node at each level and collapses
+// that run down to a single node by bypassing the rest. Since a node
+// only ever sits directly in front of the full run for its key (any
+// later duplicate is spliced in front of it, not behind), and collapsing
+// is idempotent and safe to race, the run converges to exactly one node
+// regardless of how many goroutines raced to Put the same key. The
+// collapsed nodes' arena space is not reclaimed; it simply becomes
+// unreachable.
+func (s *SkipList) Put(key, val []byte) {
+	height := randomHeight()
+
+	for {
+		listHeight := int(s.height.Load())
+		if height <= listHeight {
+			break
+		}
+		if s.height.CompareAndSwap(int32(listHeight), int32(height)) {
+			break
+		}
+	}
+
+	offset := s.arena.newNode(key, val, height)
+	newNode := node{s.arena, offset}
+
+	prevs, nexts := s.findSplice(key)
+
+	for level := 0; level < height; level++ {
+		prev, next := prevs[level], nexts[level]
+		for {
+			newNode.storeNext(level, next.offset)
+			if prev.casNext(level, next.offset, offset) {
+				break
+			}
+			prev, next = s.findSpliceForLevel(key, level, prev)
+		}
+		s.collapseDuplicates(level, newNode, key)
+	}
+}
+
+// collapseDuplicates walks forward from newNode at level, bypassing
+// every immediately-following node whose key equals key, until it finds
+// one that doesn't (or the end of the level). It re-reads newNode's
+// current forward pointer on every iteration and retries the CAS on
+// failure, so it still converges if another goroutine is concurrently
+// extending or collapsing the same run.
+func (s *SkipList) collapseDuplicates(level int, newNode node, key []byte) {
+	for {
+		nextOffset := newNode.loadNext(level)
+		if nextOffset == 0 {
+			return
+		}
+		next := node{s.arena, nextOffset}
+		if !bytes.Equal(next.key(), key) {
+			return
+		}
+		newNode.casNext(level, nextOffset, next.loadNext(level))
+	}
+}
+
+// Iterator is a concurrent-safe, forward-only iterator over a SkipList.
+// It observes a live view of the list: nodes inserted after Seek/Next was
+// last called but ordered after the current position may or may not be
+// observed, but nodes already linked in are never missed.
+type Iterator struct {
+	list *SkipList
+	n    node
+}
+
+// Iterator returns a new Iterator positioned before the first element.
+func (s *SkipList) Iterator() *Iterator {
+	return &Iterator{list: s, n: s.headNode()}
+}
+
+// Next advances the iterator and reports whether it is positioned on a
+// valid element.
+func (it *Iterator) Next() bool {
+	nextOffset := it.n.loadNext(0)
+	if nextOffset == 0 {
+		return false
+	}
+	it.n = node{it.list.arena, nextOffset}
+	return true
+}
+
+// Seek advances the iterator to the first element whose key is >= key.
+func (it *Iterator) Seek(key []byte) bool {
+	n := it.list.findGreaterOrEqual(key)
+	if n.isNil() {
+		return false
+	}
+	it.n = n
+	return true
+}
+
+// Key returns the key at the iterator's current position.
+func (it *Iterator) Key() []byte {
+	return it.n.key()
+}
+
+// Value returns the value at the iterator's current position.
+func (it *Iterator) Value() []byte {
+	return it.n.value()
+}
@@ -0,0 +1,176 @@
+package skiplist
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Codec defines how individual keys and values are serialized to and
+// from a stream by WriteTo/MarshalBinary and ReadFrom/UnmarshalBinary.
+// DecodeKey must return io.EOF (and nothing else) when called at a
+// clean entry boundary with no more data left to read.
+type Codec[K, V any] struct {
+	EncodeKey   func(w io.Writer, key K) error
+	DecodeKey   func(r io.Reader) (K, error)
+	EncodeValue func(w io.Writer, value V) error
+	DecodeValue func(r io.Reader) (V, error)
+}
+
+// ErrNoCodec is returned by WriteTo, ReadFrom, MarshalBinary and
+// UnmarshalBinary when SetCodec has not been called yet.
+var ErrNoCodec = errors.New("skiplist: no codec set; call SetCodec first")
+
+// SetCodec configures the Codec that WriteTo/MarshalBinary and
+// ReadFrom/UnmarshalBinary use to serialize s's entries.
+func (s *SkipList[K, V]) SetCodec(codec Codec[K, V]) {
+	s.codec = codec
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo serializes the ordered key/value sequence of s, via a level-0
+// walk, using the codec set with SetCodec. It returns the number of
+// bytes written.
+func (s *SkipList[K, V]) WriteTo(w io.Writer) (int64, error) {
+	if s.codec.EncodeKey == nil || s.codec.EncodeValue == nil {
+		return 0, ErrNoCodec
+	}
+
+	cw := &countingWriter{w: w}
+	for n := s.header.next(); n != nil; n = n.next() {
+		if err := s.codec.EncodeKey(cw, n.key); err != nil {
+			return cw.n, err
+		}
+		if err := s.codec.EncodeValue(cw, n.value); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+// MarshalBinary serializes the ordered key/value sequence of s using the
+// codec set with SetCodec. It is a convenience wrapper around WriteTo
+// for callers that want an in-memory checkpoint rather than a stream.
+func (s *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// reset discards s's contents, keeping its comparison function, codec
+// and level/probability configuration.
+func (s *SkipList[K, V]) reset() {
+	s.header = &node[K, V]{
+		forward: []*node[K, V]{nil},
+		span:    []int{0},
+	}
+	s.footer = nil
+	s.length = 0
+	s.update = make([]*node[K, V], s.maxLevel+1)
+	s.rank = make([]int, s.maxLevel+1)
+}
+
+// ReadFrom replaces s's contents with the sequence read from r, as
+// written by WriteTo or MarshalBinary with the same codec (set with
+// SetCodec). Rather than re-running getPath (a full descent from the
+// header) for every key, ReadFrom walks the input once: it chooses each
+// node's random level up front and stitches forward pointers (and their
+// spans) directly onto the last-seen node at that level, for an O(n)
+// rebuild.
+func (s *SkipList[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	if s.codec.DecodeKey == nil || s.codec.DecodeValue == nil {
+		return 0, ErrNoCodec
+	}
+	codec := s.codec
+	s.reset()
+
+	cr := &countingReader{r: r}
+
+	tails := make([]*node[K, V], s.maxLevel+1)
+	tailIndex := make([]int, s.maxLevel+1)
+	for i := range tails {
+		tails[i] = s.header
+	}
+
+	index := 0
+	for {
+		key, err := codec.DecodeKey(cr)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cr.n, err
+		}
+		value, err := codec.DecodeValue(cr)
+		if err != nil {
+			return cr.n, err
+		}
+		index++
+
+		level := s.randomLevel()
+		if level > s.level() {
+			for i := s.level() + 1; i <= level; i++ {
+				s.header.forward = append(s.header.forward, nil)
+				s.header.span = append(s.header.span, 0)
+				tails[i] = s.header
+				tailIndex[i] = 0
+			}
+		}
+
+		newNode := &node[K, V]{
+			forward: make([]*node[K, V], level+1, s.effectiveMaxLevel()+1),
+			span:    make([]int, level+1, s.effectiveMaxLevel()+1),
+			key:     key,
+			value:   value,
+		}
+		if tails[0] != s.header {
+			newNode.backward = tails[0]
+		}
+		for i := 0; i <= level; i++ {
+			tails[i].span[i] = index - tailIndex[i]
+			tails[i].forward[i] = newNode
+			tails[i] = newNode
+			tailIndex[i] = index
+		}
+
+		s.length++
+		s.footer = newNode
+	}
+
+	for i := 0; i <= s.level(); i++ {
+		tails[i].span[i] = index + 1 - tailIndex[i]
+	}
+
+	return cr.n, nil
+}
+
+// UnmarshalBinary replaces s's contents with the sequence encoded in
+// data, as produced by MarshalBinary with the same codec (set with
+// SetCodec).
+func (s *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
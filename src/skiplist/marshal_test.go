@@ -0,0 +1,116 @@
+package skiplist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func intCodec() Codec[int, string] {
+	return Codec[int, string]{
+		EncodeKey: func(w io.Writer, key int) error {
+			return binary.Write(w, binary.LittleEndian, int64(key))
+		},
+		DecodeKey: func(r io.Reader) (int, error) {
+			var key int64
+			if err := binary.Read(r, binary.LittleEndian, &key); err != nil {
+				return 0, err
+			}
+			return int(key), nil
+		},
+		EncodeValue: func(w io.Writer, value string) error {
+			if err := binary.Write(w, binary.LittleEndian, int32(len(value))); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte(value))
+			return err
+		},
+		DecodeValue: func(r io.Reader) (string, error) {
+			var size int32
+			if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+				return "", err
+			}
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return "", err
+			}
+			return string(buf), nil
+		},
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	s := NewOrdered[int, string]()
+	s.SetCodec(intCodec())
+	want := map[int]string{1: "one", 2: "two", 3: "three", 42: "the answer"}
+	for k, v := range want {
+		s.Set(k, v)
+	}
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	out := NewOrdered[int, string]()
+	out.SetCodec(intCodec())
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	if out.length != len(want) {
+		t.Fatalf("length = %d, want %d", out.length, len(want))
+	}
+	for k, v := range want {
+		got, ok := out.Get(k)
+		if !ok || got != v {
+			t.Errorf("Get(%d) = (%q, %v), want (%q, true)", k, got, ok, v)
+		}
+		if rank := out.Rank(k); rank == 0 {
+			t.Errorf("Rank(%d) = 0 after round trip, want nonzero", k)
+		}
+	}
+}
+
+func TestWriteToReadFromStream(t *testing.T) {
+	s := NewOrdered[int, string]()
+	s.SetCodec(intCodec())
+	for i := 0; i < 200; i++ {
+		s.Set(i, string(rune('a'+i%26)))
+	}
+
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, buffer has %d bytes", n, buf.Len())
+	}
+
+	out := NewOrdered[int, string]()
+	out.SetCodec(intCodec())
+	if _, err := out.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+
+	for i := 0; i < 200; i++ {
+		got, ok := out.Get(i)
+		want := string(rune('a' + i%26))
+		if !ok || got != want {
+			t.Errorf("Get(%d) = (%q, %v), want (%q, true)", i, got, ok, want)
+		}
+	}
+}
+
+func TestMarshalNoCodec(t *testing.T) {
+	s := NewOrdered[int, string]()
+	s.Set(1, "one")
+	if _, err := s.MarshalBinary(); err != ErrNoCodec {
+		t.Errorf("MarshalBinary with no codec set = %v, want ErrNoCodec", err)
+	}
+	if err := s.UnmarshalBinary(nil); err != ErrNoCodec {
+		t.Errorf("UnmarshalBinary with no codec set = %v, want ErrNoCodec", err)
+	}
+}
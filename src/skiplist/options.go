@@ -0,0 +1,60 @@
+package skiplist
+
+import "math/rand"
+
+// SkipListOptions configures a SkipList built with NewWithOptions.
+type SkipListOptions struct {
+	// MaxLevel caps the highest level a node can reach; levels count from
+	// 0, so a node has at most MaxLevel+1 forward pointers. It
+	// should be sized for the expected number of items: DefaultMaxLevel
+	// (32) comfortably covers lists up to 2^32 items, but a list that
+	// will only ever hold a few thousand entries can use a much smaller
+	// cap, e.g. 12 for <=4096 items, to save a pointer slot per node. If
+	// zero, DefaultMaxLevel is used.
+	MaxLevel int
+
+	// P is the fraction of nodes at level i that also have a level i+1
+	// pointer. Lower P (e.g. 1/4) uses less memory; higher P (e.g. 1/2)
+	// gives more consistent operation times at the cost of memory. If
+	// zero, 0.25 is used.
+	P float64
+
+	// Source seeds the list's own *rand.Rand for choosing node levels.
+	// Supplying one makes level choices reproducible (useful in tests)
+	// and avoids contending on the global math/rand source under
+	// concurrent use. If nil, the global source is used.
+	Source rand.Source
+}
+
+// NewWithOptions returns a new SkipList that uses less as the comparison
+// function, configured by opts. less should define a linear order on
+// keys you intend to use with the SkipList.
+func NewWithOptions[K, V any](less func(a, b K) bool, opts SkipListOptions) *SkipList[K, V] {
+	maxLevel := opts.MaxLevel
+	if maxLevel <= 0 {
+		maxLevel = DefaultMaxLevel
+	}
+
+	prob := opts.P
+	if prob <= 0 {
+		prob = p
+	}
+
+	var rng *rand.Rand
+	if opts.Source != nil {
+		rng = rand.New(opts.Source)
+	}
+
+	return &SkipList[K, V]{
+		lessThan: less,
+		header: &node[K, V]{
+			forward: []*node[K, V]{nil},
+			span:    []int{0},
+		},
+		update:   make([]*node[K, V], maxLevel+1),
+		rank:     make([]int, maxLevel+1),
+		maxLevel: maxLevel,
+		p:        prob,
+		rng:      rng,
+	}
+}
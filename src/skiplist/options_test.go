@@ -0,0 +1,69 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+	s := NewWithOptions[int, int](func(a, b int) bool { return a < b }, SkipListOptions{})
+	if s.maxLevel != DefaultMaxLevel {
+		t.Errorf("maxLevel = %d, want DefaultMaxLevel (%d) when MaxLevel is zero", s.maxLevel, DefaultMaxLevel)
+	}
+	if s.p != p {
+		t.Errorf("p = %v, want %v when P is zero", s.p, p)
+	}
+
+	// A freshly constructed list should behave correctly regardless of
+	// the options used to build it.
+	s.Set(1, 2)
+	if v, ok := s.Get(1); !ok || v != 2 {
+		t.Errorf("Get(1) = (%d, %v), want (2, true)", v, ok)
+	}
+}
+
+func TestNewWithOptionsCustom(t *testing.T) {
+	opts := SkipListOptions{
+		MaxLevel: 4,
+		P:        0.5,
+		Source:   rand.NewSource(1),
+	}
+	s := NewWithOptions[int, string](func(a, b int) bool { return a < b }, opts)
+
+	if s.maxLevel != 4 {
+		t.Errorf("maxLevel = %d, want 4", s.maxLevel)
+	}
+	if s.p != 0.5 {
+		t.Errorf("p = %v, want 0.5", s.p)
+	}
+
+	for i := 0; i < 50; i++ {
+		s.Set(i, "")
+	}
+	if s.Len() != 50 {
+		t.Errorf("Len() = %d after 50 Sets, want 50", s.Len())
+	}
+	for i := 0; i < 50; i++ {
+		if _, ok := s.Get(i); !ok {
+			t.Errorf("Get(%d) = not found, want found", i)
+		}
+	}
+}
+
+func TestNewWithOptionsReproducibleSource(t *testing.T) {
+	opts := func() SkipListOptions {
+		return SkipListOptions{Source: rand.NewSource(42)}
+	}
+
+	a := NewWithOptions[int, int](func(x, y int) bool { return x < y }, opts())
+	b := NewWithOptions[int, int](func(x, y int) bool { return x < y }, opts())
+
+	for i := 0; i < 100; i++ {
+		a.Set(i, i)
+		b.Set(i, i)
+	}
+
+	if a.level() != b.level() {
+		t.Errorf("lists built from the same Source seed ended up at different levels: %d vs %d", a.level(), b.level())
+	}
+}
@@ -0,0 +1,93 @@
+package skiplist
+
+import "testing"
+
+func TestRange(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 10; i++ {
+		s.Set(i, i)
+	}
+
+	it := s.Range(3, 7)
+	var got []int
+	for it.Next() {
+		got = append(got, it.Key())
+	}
+	want := []int{3, 4, 5, 6}
+	if len(got) != len(want) {
+		t.Fatalf("Range(3, 7) yielded %v, want %v", got, want)
+	}
+	for i, k := range want {
+		if got[i] != k {
+			t.Errorf("Range(3, 7)[%d] = %d, want %d", i, got[i], k)
+		}
+	}
+}
+
+func TestRangeReverse(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 10; i++ {
+		s.Set(i, i)
+	}
+
+	it := s.RangeReverse(3, 7)
+	var got []int
+	for it.Previous() {
+		got = append(got, it.Key())
+	}
+	want := []int{6, 5, 4, 3}
+	if len(got) != len(want) {
+		t.Fatalf("RangeReverse(3, 7) yielded %v, want %v", got, want)
+	}
+	for i, k := range want {
+		if got[i] != k {
+			t.Errorf("RangeReverse(3, 7)[%d] = %d, want %d", i, got[i], k)
+		}
+	}
+}
+
+func TestIteratorBetween(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 5; i++ {
+		s.Set(i, i*10)
+	}
+
+	it := s.IteratorBetween(1, 4)
+	var got []int
+	for it.Next() {
+		got = append(got, it.Key())
+	}
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("IteratorBetween(1, 4) yielded %v, want %v", got, want)
+	}
+	for i, k := range want {
+		if got[i] != k {
+			t.Errorf("IteratorBetween(1, 4)[%d] = %d, want %d", i, got[i], k)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s := NewOrdered[int, int]()
+	s.Set(1, 1)
+	s.Set(2, 2)
+
+	snap := s.Snapshot()
+
+	s.Set(3, 3)
+	s.Delete(1)
+
+	if _, ok := snap.Get(3); ok {
+		t.Error("Snapshot observed a Set made after it was taken")
+	}
+	if v, ok := snap.Get(1); !ok || v != 1 {
+		t.Errorf("Snapshot no longer has key 1 after a later Delete on the source list: Get(1) = (%d, %v)", v, ok)
+	}
+	if snap.Len() != 2 {
+		t.Errorf("snap.Len() = %d, want 2", snap.Len())
+	}
+	if s.Len() != 2 {
+		t.Errorf("source list Len() = %d after Set(3)+Delete(1), want 2", s.Len())
+	}
+}
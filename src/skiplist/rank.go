@@ -0,0 +1,63 @@
+package skiplist
+
+// GetByRank returns the key and value of the i-th smallest element in s,
+// where i is 1-based (GetByRank(1) is the smallest element), and whether
+// such an element exists. It runs in O(log n) using the span counts
+// maintained by Set and Delete.
+func (s *SkipList[K, V]) GetByRank(i int) (key K, value V, ok bool) {
+	if i < 1 || i > s.length {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+
+	current := s.header
+	traversed := 0
+	for level := s.level(); level >= 0; level-- {
+		for current.forward[level] != nil && traversed+current.span[level] <= i {
+			traversed += current.span[level]
+			current = current.forward[level]
+		}
+		if traversed == i {
+			return current.key, current.value, true
+		}
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Rank returns the 1-based rank of key in s, or 0 if key is not present.
+// It runs in O(log n) using the span counts maintained by Set and
+// Delete.
+func (s *SkipList[K, V]) Rank(key K) int {
+	current := s.header
+	rank := 0
+	for level := s.level(); level >= 0; level-- {
+		// Advance through nodes ordering at or before key, so that a
+		// node exactly equal to key is the one we land on, not the one
+		// just before it.
+		for current.forward[level] != nil && !s.lessThan(key, current.forward[level].key) {
+			rank += current.span[level]
+			current = current.forward[level]
+		}
+		if current != s.header && s.equal(current.key, key) {
+			// rank already counts current itself, via the span that
+			// stepped onto it, so it's already the 1-based rank.
+			return rank
+		}
+	}
+	return 0
+}
+
+// GetByRank returns the key and value of the i-th smallest element in m
+// (1-based), and whether such an element exists.
+func (m *Map) GetByRank(i int) (key, value interface{}, ok bool) {
+	return m.inner.GetByRank(i)
+}
+
+// Rank returns the 1-based rank of key in m, or 0 if key is not present.
+func (m *Map) Rank(key interface{}) int {
+	return m.inner.Rank(key)
+}
@@ -0,0 +1,118 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// sortedKeys returns the keys currently in s, via a level-0 walk, in
+// ascending order.
+func sortedKeys(s *SkipList[int, int]) []int {
+	var got []int
+	for n := s.header.next(); n != nil; n = n.next() {
+		got = append(got, n.key)
+	}
+	return got
+}
+
+func TestRankAndGetByRank(t *testing.T) {
+	s := NewOrdered[int, int]()
+	keys := []int{5, 1, 9, 3, 7}
+	for _, k := range keys {
+		s.Set(k, k*10)
+	}
+
+	sorted := append([]int(nil), keys...)
+	sort.Ints(sorted)
+
+	for i, k := range sorted {
+		if rank := s.Rank(k); rank != i+1 {
+			t.Errorf("Rank(%d) = %d, want %d", k, rank, i+1)
+		}
+		gotKey, gotValue, ok := s.GetByRank(i + 1)
+		if !ok || gotKey != k || gotValue != k*10 {
+			t.Errorf("GetByRank(%d) = (%d, %d, %v), want (%d, %d, true)", i+1, gotKey, gotValue, ok, k, k*10)
+		}
+	}
+
+	if rank := s.Rank(100); rank != 0 {
+		t.Errorf("Rank(100) = %d, want 0 for an absent key", rank)
+	}
+	if _, _, ok := s.GetByRank(0); ok {
+		t.Error("GetByRank(0) = ok, want false")
+	}
+	if _, _, ok := s.GetByRank(len(keys) + 1); ok {
+		t.Error("GetByRank(len(keys)+1) = ok, want false")
+	}
+}
+
+// TestDeleteHeaderShrink reproduces a crash that used to happen when
+// enough high-level nodes were deleted that the header's own level had
+// to shrink: the shrink loop read s.level() again after truncating
+// s.header.forward, so s.header.span ended up one element shorter than
+// s.header.forward, and the next getPath call to descend into the lost
+// level panicked on an out-of-range index into span.
+func TestDeleteHeaderShrink(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 2000; i++ {
+		s.Set(i, i)
+	}
+	for i := 0; i < 2000; i++ {
+		if _, ok := s.Delete(i); !ok {
+			t.Fatalf("Delete(%d) = false, want true", i)
+		}
+	}
+	if s.length != 0 {
+		t.Fatalf("length = %d after deleting every key, want 0", s.length)
+	}
+	if got := len(s.header.forward); got != len(s.header.span) {
+		t.Fatalf("header forward/span length mismatch: len(forward)=%d, len(span)=%d", got, len(s.header.span))
+	}
+}
+
+// TestSetDeleteFuzz exercises Set/Delete with random operations over a
+// small key space, checking after every step that the level-0 sequence
+// stays sorted and that span/Rank stay consistent with it. This is the
+// shape of fuzzing that originally found the header shrink bug fixed by
+// TestDeleteHeaderShrink.
+func TestSetDeleteFuzz(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewOrdered[int, int]()
+	present := map[int]bool{}
+
+	const keySpace = 2000
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(keySpace)
+		if rng.Intn(2) == 0 {
+			s.Set(key, key)
+			present[key] = true
+		} else {
+			s.Delete(key)
+			delete(present, key)
+		}
+
+		if i%1000 != 0 {
+			continue
+		}
+
+		got := sortedKeys(s)
+		var want []int
+		for k := range present {
+			want = append(want, k)
+		}
+		sort.Ints(want)
+
+		if len(got) != len(want) {
+			t.Fatalf("op %d: length = %d, want %d", i, len(got), len(want))
+		}
+		for j, k := range want {
+			if got[j] != k {
+				t.Fatalf("op %d: sortedKeys[%d] = %d, want %d", i, j, got[j], k)
+			}
+			if rank := s.Rank(k); rank != j+1 {
+				t.Fatalf("op %d: Rank(%d) = %d, want %d", i, k, rank, j+1)
+			}
+		}
+	}
+}
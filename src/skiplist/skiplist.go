@@ -12,10 +12,17 @@
 // Skip lists were first described in Pugh, William (June 1990). "Skip
 // lists: a probabilistic alternative to balanced
 // trees". Communications of the ACM 33 (6): 668–676
+//
+// The primary type is the generic SkipList[K, V]. Callers that have
+// not migrated to generics can keep using the interface{}-keyed Map
+// and its NewXxxMap constructors, which are thin wrappers around
+// SkipList[interface{}, interface{}].
 package skiplist
 
 import (
 	"math/rand"
+
+	"golang.org/x/exp/constraints"
 )
 
 // p is the fraction of nodes with level i pointers that also have
@@ -27,15 +34,20 @@ const p = 0.25
 const DefaultMaxLevel = 32
 
 // A node is a container for key-value pairs that are stored in a skip
-// list.
-type node struct {
-	forward    []*node
-	backward   *node
-	key, value interface{}
+// list. span[i] is the distance, in level-0 steps, from this node to
+// forward[i] (1 when forward[i] is also the next node at level 0), which
+// lets GetByRank and Rank answer order-statistic queries in O(log n)
+// instead of a level-0 walk.
+type node[K, V any] struct {
+	forward    []*node[K, V]
+	span       []int
+	backward   *node[K, V]
+	key        K
+	value      V
 }
 
 // next returns the next node in the skip list containing n.
-func (n *node) next() *node {
+func (n *node[K, V]) next() *node[K, V] {
 	if len(n.forward) == 0 {
 		return nil
 	}
@@ -43,17 +55,17 @@ func (n *node) next() *node {
 }
 
 // previous returns the previous node in the skip list containing n.
-func (n *node) previous() *node {
+func (n *node[K, V]) previous() *node[K, V] {
 	return n.backward
 }
 
 // hasNext returns true if n has a next node.
-func (n *node) hasNext() bool {
+func (n *node[K, V]) hasNext() bool {
 	return n.next() != nil
 }
 
 // hasPrevious returns true if n has a previous node.
-func (n *node) hasPrevious() bool {
+func (n *node[K, V]) hasPrevious() bool {
 	return n.previous() != nil
 }
 
@@ -63,30 +75,41 @@ func (n *node) hasPrevious() bool {
 // 2^MaxLevel items.
 //
 // To iterate over a skip list (where s is a
-// *SkipList):
+// *SkipList[K, V]):
 //
 //	for i := s.Iterator(); i.Next(); {
 //		// do something with i.Key() and i.Value()
 //	}
-type SkipList struct {
-	lessThan func(l, r interface{}) bool
-	header   *node
-	footer   *node
+type SkipList[K, V any] struct {
+	lessThan func(l, r K) bool
+	header   *node[K, V]
+	footer   *node[K, V]
 	length   int
-	update   []*node
+	update   []*node[K, V]
+	rank     []int
+	maxLevel int
+	p        float64
+	rng      *rand.Rand
+	codec    Codec[K, V]
 }
 
 // Len returns the length of s.
-func (s *SkipList) Len() int {
+func (s *SkipList[K, V]) Len() int {
 	return s.length
 }
 
+// equal reports whether a and b are equivalent under s.lessThan, i.e.
+// neither orders before the other.
+func (s *SkipList[K, V]) equal(a, b K) bool {
+	return !s.lessThan(a, b) && !s.lessThan(b, a)
+}
+
 // Iterator is an interface that you can use to iterate through the
 // skip list (in its entirety or fragments). For an use example, see
 // the documentation of SkipList.
 //
 // Key and Value return the key and the value of the current node.
-type Iterator interface {
+type Iterator[K, V any] interface {
 	// Next returns true if the iterator contains subsequent elements
 	// and advances its state to the next element if that is possible.
 	Next() (ok bool)
@@ -94,36 +117,39 @@ type Iterator interface {
 	// and rewinds its state to the previous element if that is possible.
 	Previous() (ok bool)
 	// Key returns the current key.
-	Key() interface{}
+	Key() K
 	// Value returns the current value.
-	Value() interface{}
+	Value() V
 	// Seek reduces iterative seek costs for searching forward into the Skip List
 	// by remarking the range of keys over which it has scanned before.  If the
 	// requested key occurs prior to the point, the Skip List will start searching
 	// as a safeguard.  It returns true if the key is within the known range of
 	// the list.
-	Seek(key interface{}) (ok bool)
+	Seek(key K) (ok bool)
+	// Clone duplicates the iterator's current position in O(1), so a
+	// caller can fork a second cursor without re-walking the list.
+	Clone() Iterator[K, V]
 	// Close this iterator to reap resources associated with it.  While not
 	// strictly required, it will provide extra hints for the garbage collector.
 	Close()
 }
 
-type iter struct {
-	current *node
-	key     interface{}
-	list    *SkipList
-	value   interface{}
+type iter[K, V any] struct {
+	current *node[K, V]
+	key     K
+	list    *SkipList[K, V]
+	value   V
 }
 
-func (i iter) Key() interface{} {
+func (i iter[K, V]) Key() K {
 	return i.key
 }
 
-func (i iter) Value() interface{} {
+func (i iter[K, V]) Value() V {
 	return i.value
 }
 
-func (i *iter) Next() bool {
+func (i *iter[K, V]) Next() bool {
 	if !i.current.hasNext() {
 		return false
 	}
@@ -135,7 +161,7 @@ func (i *iter) Next() bool {
 	return true
 }
 
-func (i *iter) Previous() bool {
+func (i *iter[K, V]) Previous() bool {
 	if !i.current.hasPrevious() {
 		return false
 	}
@@ -147,7 +173,7 @@ func (i *iter) Previous() bool {
 	return true
 }
 
-func (i *iter) Seek(key interface{}) (ok bool) {
+func (i *iter[K, V]) Seek(key K) (ok bool) {
 	current := i.current
 	list := i.list
 
@@ -160,7 +186,7 @@ func (i *iter) Seek(key interface{}) (ok bool) {
 	// If the target key occurs before the current key, we cannot take advantage
 	// of the heretofore spent traversal cost to find it; resetting back to the
 	// beginning is the safest choice.
-	if current.key != nil && list.lessThan(key, current.key) {
+	if current != list.header && list.lessThan(key, current.key) {
 		current = list.header
 	}
 
@@ -172,7 +198,7 @@ func (i *iter) Seek(key interface{}) (ok bool) {
 		current = current.backward
 	}
 
-	current = list.getPath(current, nil, key)
+	current = list.getPath(current, nil, nil, key)
 
 	if current == nil {
 		return
@@ -185,20 +211,22 @@ func (i *iter) Seek(key interface{}) (ok bool) {
 	return true
 }
 
-func (i *iter) Close() {
-	i.key = nil
-	i.value = nil
+func (i *iter[K, V]) Close() {
+	var zeroK K
+	var zeroV V
+	i.key = zeroK
+	i.value = zeroV
 	i.current = nil
 	i.list = nil
 }
 
-type rangeIterator struct {
-	iter
-	upperLimit interface{}
-	lowerLimit interface{}
+type rangeIterator[K, V any] struct {
+	iter[K, V]
+	upperLimit K
+	lowerLimit K
 }
 
-func (i *rangeIterator) Next() bool {
+func (i *rangeIterator[K, V]) Next() bool {
 	if !i.current.hasNext() {
 		return false
 	}
@@ -215,7 +243,7 @@ func (i *rangeIterator) Next() bool {
 	return true
 }
 
-func (i *rangeIterator) Previous() bool {
+func (i *rangeIterator[K, V]) Previous() bool {
 	if !i.current.hasPrevious() {
 		return false
 	}
@@ -232,7 +260,7 @@ func (i *rangeIterator) Previous() bool {
 	return true
 }
 
-func (i *rangeIterator) Seek(key interface{}) (ok bool) {
+func (i *rangeIterator[K, V]) Seek(key K) (ok bool) {
 	if i.list.lessThan(key, i.lowerLimit) {
 		return
 	} else if !i.list.lessThan(key, i.upperLimit) {
@@ -242,15 +270,16 @@ func (i *rangeIterator) Seek(key interface{}) (ok bool) {
 	return i.iter.Seek(key)
 }
 
-func (i *rangeIterator) Close() {
+func (i *rangeIterator[K, V]) Close() {
 	i.iter.Close()
-	i.upperLimit = nil
-	i.lowerLimit = nil
+	var zero K
+	i.upperLimit = zero
+	i.lowerLimit = zero
 }
 
 // Iterator returns an Iterator that will go through all elements s.
-func (s *SkipList) Iterator() Iterator {
-	return &iter{
+func (s *SkipList[K, V]) Iterator() Iterator[K, V] {
+	return &iter[K, V]{
 		current: s.header,
 		list:    s,
 	}
@@ -258,13 +287,13 @@ func (s *SkipList) Iterator() Iterator {
 
 // Seek returns a bidirectional iterator starting with the first element whose
 // key is greater or equal to key; otherwise, a nil iterator is returned.
-func (s *SkipList) Seek(key interface{}) Iterator {
-	current := s.getPath(s.header, nil, key)
+func (s *SkipList[K, V]) Seek(key K) Iterator[K, V] {
+	current := s.getPath(s.header, nil, nil, key)
 	if current == nil {
 		return nil
 	}
 
-	return &iter{
+	return &iter[K, V]{
 		current: current,
 		key:     current.key,
 		list:    s,
@@ -274,14 +303,14 @@ func (s *SkipList) Seek(key interface{}) Iterator {
 
 // SeekToFirst returns a bidirectional iterator starting from the first element
 // in the list if the list is populated; otherwise, a nil iterator is returned.
-func (s *SkipList) SeekToFirst() Iterator {
+func (s *SkipList[K, V]) SeekToFirst() Iterator[K, V] {
 	if s.length == 0 {
 		return nil
 	}
 
 	current := s.header.next()
 
-	return &iter{
+	return &iter[K, V]{
 		current: current,
 		key:     current.key,
 		list:    s,
@@ -291,13 +320,13 @@ func (s *SkipList) SeekToFirst() Iterator {
 
 // SeekToLast returns a bidirectional iterator starting from the last element
 // in the list if the list is populated; otherwise, a nil iterator is returned.
-func (s *SkipList) SeekToLast() Iterator {
+func (s *SkipList[K, V]) SeekToLast() Iterator[K, V] {
 	current := s.footer
 	if current == nil {
 		return nil
 	}
 
-	return &iter{
+	return &iter[K, V]{
 		current: current,
 		key:     current.key,
 		list:    s,
@@ -308,12 +337,12 @@ func (s *SkipList) SeekToLast() Iterator {
 // Range returns an iterator that will go through all the
 // elements of the skip list that are greater or equal than from, but
 // less than to.
-func (s *SkipList) Range(from, to interface{}) Iterator {
-	start := s.getPath(s.header, nil, from)
-	return &rangeIterator{
-		iter: iter{
-			current: &node{
-				forward:  []*node{start},
+func (s *SkipList[K, V]) Range(from, to K) Iterator[K, V] {
+	start := s.getPath(s.header, nil, nil, from)
+	return &rangeIterator[K, V]{
+		iter: iter[K, V]{
+			current: &node[K, V]{
+				forward:  []*node[K, V]{start},
 				backward: start,
 			},
 			list: s,
@@ -323,36 +352,39 @@ func (s *SkipList) Range(from, to interface{}) Iterator {
 	}
 }
 
-func (s *SkipList) level() int {
+func (s *SkipList[K, V]) level() int {
 	return len(s.header.forward) - 1
 }
 
-func maxInt(x, y int) int {
-	if x > y {
-		return x
-	}
-	return y
+func (s *SkipList[K, V]) effectiveMaxLevel() int {
+	return s.maxLevel
 }
 
-func (s *SkipList) effectiveMaxLevel() int {
-	return maxInt(s.level(), DefaultMaxLevel)
+// randFloat64 draws from the list's own *rand.Rand if NewWithOptions was
+// given a Source, falling back to the shared global source otherwise.
+func (s *SkipList[K, V]) randFloat64() float64 {
+	if s.rng != nil {
+		return s.rng.Float64()
+	}
+	return rand.Float64()
 }
 
 // Returns a new random level.
-func (s SkipList) randomLevel() (n int) {
-	for n = 0; n < s.effectiveMaxLevel() && rand.Float64() < p; n++ {
+func (s *SkipList[K, V]) randomLevel() (n int) {
+	for n = 0; n < s.effectiveMaxLevel() && s.randFloat64() < s.p; n++ {
 	}
 	return
 }
 
-// Get returns the value associated with key from s (nil if the key is
-// not present in s). The second return value is true when the key is
-// present.
-func (s *SkipList) Get(key interface{}) (value interface{}, ok bool) {
-	candidate := s.getPath(s.header, nil, key)
+// Get returns the value associated with key from s (the zero value of V
+// if the key is not present in s). The second return value is true when
+// the key is present.
+func (s *SkipList[K, V]) Get(key K) (value V, ok bool) {
+	candidate := s.getPath(s.header, nil, nil, key)
 
-	if candidate == nil || candidate.key != key {
-		return nil, false
+	if candidate == nil || !s.equal(candidate.key, key) {
+		var zero V
+		return zero, false
 	}
 
 	return candidate.value, true
@@ -361,13 +393,15 @@ func (s *SkipList) Get(key interface{}) (value interface{}, ok bool) {
 // GetGreaterOrEqual finds the node whose key is greater than or equal
 // to min. It returns its value, its actual key, and whether such a
 // node is present in the skip list.
-func (s *SkipList) GetGreaterOrEqual(min interface{}) (actualKey, value interface{}, ok bool) {
-	candidate := s.getPath(s.header, nil, min)
+func (s *SkipList[K, V]) GetGreaterOrEqual(min K) (actualKey K, value V, ok bool) {
+	candidate := s.getPath(s.header, nil, nil, min)
 
 	if candidate != nil {
 		return candidate.key, candidate.value, true
 	}
-	return nil, nil, false
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
 }
 
 // getPath populates update with nodes that constitute the path to the
@@ -375,30 +409,38 @@ func (s *SkipList) GetGreaterOrEqual(min interface{}) (actualKey, value interfac
 // update is nil, it will be left alone (the candidate node will still
 // be returned). If update is not nil, but it doesn't have enough
 // slots for all the nodes in the path, getPath will panic.
-func (s *SkipList) getPath(current *node, update []*node, key interface{}) *node {
+//
+// If rank is not nil, rank[i] is populated with the rank of update[i]:
+// the number of level-0 nodes stepped past by the top-down descent, at
+// levels depth down to i, before reaching update[i] (0 for the header);
+// Set uses this to keep span counts correct.
+func (s *SkipList[K, V]) getPath(current *node[K, V], update []*node[K, V], rank []int, key K) *node[K, V] {
 	depth := len(current.forward) - 1
+	var traversed int
 
 	for i := depth; i >= 0; i-- {
 		for current.forward[i] != nil && s.lessThan(current.forward[i].key, key) {
+			traversed += current.span[i]
 			current = current.forward[i]
 		}
 		if update != nil {
 			update[i] = current
 		}
+		if rank != nil {
+			rank[i] = traversed
+		}
 	}
 	return current.next()
 }
 
-// Sets set the value associated with key in s.
-func (s *SkipList) Set(key, value interface{}) {
-	if key == nil {
-		return
-	}
+// Set sets the value associated with key in s.
+func (s *SkipList[K, V]) Set(key K, value V) {
 	// s.level starts from 0, so we need to allocate one.
 	update := s.update[:s.level()+1]
-	candidate := s.getPath(s.header, update, key)
+	rank := s.rank[:s.level()+1]
+	candidate := s.getPath(s.header, update, rank, key)
 
-	if candidate != nil && candidate.key == key {
+	if candidate != nil && s.equal(candidate.key, key) {
 		candidate.value = value
 		return
 	}
@@ -408,26 +450,44 @@ func (s *SkipList) Set(key, value interface{}) {
 	if currentLevel := s.level(); newLevel > currentLevel {
 		// there are no pointers for the higher levels in
 		// update. Header should be there. Also add higher
-		// level links to the header.
+		// level links to the header, each currently spanning the
+		// whole list since nothing has been inserted above
+		// currentLevel yet.
 		for i := currentLevel + 1; i <= newLevel; i++ {
 			update = append(update, s.header)
+			rank = append(rank, 0)
 			s.header.forward = append(s.header.forward, nil)
+			s.header.span = append(s.header.span, s.length)
 		}
 	}
 
-	newNode := &node{
-		forward: make([]*node, newLevel+1, s.effectiveMaxLevel()+1),
+	newNode := &node[K, V]{
+		forward: make([]*node[K, V], newLevel+1, s.effectiveMaxLevel()+1),
+		span:    make([]int, newLevel+1, s.effectiveMaxLevel()+1),
 		key:     key,
 		value:   value,
 	}
 
-	if previous := update[0]; previous.key != nil {
+	if previous := update[0]; previous != s.header {
 		newNode.backward = previous
 	}
 
 	for i := 0; i <= newLevel; i++ {
 		newNode.forward[i] = update[i].forward[i]
 		update[i].forward[i] = newNode
+
+		// newNode absorbs whatever update[i] used to skip beyond the
+		// rank[0]-rank[i] nodes already accounted for below level i;
+		// update[i] in turn now only needs to skip over those plus
+		// the new node itself.
+		newNode.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = (rank[0] - rank[i]) + 1
+	}
+
+	// Levels above the new node's height were not touched above, but
+	// they now skip over one more node below them.
+	for i := newLevel + 1; i <= s.level(); i++ {
+		update[i].span[i]++
 	}
 
 	s.length++
@@ -446,15 +506,13 @@ func (s *SkipList) Set(key, value interface{}) {
 // Delete removes the node with the given key.
 //
 // It returns the old value and whether the node was present.
-func (s *SkipList) Delete(key interface{}) (value interface{}, ok bool) {
-	if key == nil {
-		return nil, false
-	}
+func (s *SkipList[K, V]) Delete(key K) (value V, ok bool) {
 	update := s.update[:s.level()+1]
-	candidate := s.getPath(s.header, update, key)
+	candidate := s.getPath(s.header, update, nil, key)
 
-	if candidate == nil || candidate.key != key {
-		return nil, false
+	if candidate == nil || !s.equal(candidate.key, key) {
+		var zero V
+		return zero, false
 	}
 
 	previous := candidate.backward
@@ -467,48 +525,139 @@ func (s *SkipList) Delete(key interface{}) (value interface{}, ok bool) {
 		next.backward = previous
 	}
 
-	for i := 0; i <= s.level() && update[i].forward[i] == candidate; i++ {
-		update[i].forward[i] = candidate.forward[i]
+	for i := 0; i <= s.level(); i++ {
+		if update[i].forward[i] == candidate {
+			update[i].span[i] += candidate.span[i] - 1
+			update[i].forward[i] = candidate.forward[i]
+		} else {
+			update[i].span[i]--
+		}
 	}
 
 	for s.level() > 0 && s.header.forward[s.level()] == nil {
-		s.header.forward = s.header.forward[:s.level()]
+		lvl := s.level()
+		s.header.forward = s.header.forward[:lvl]
+		s.header.span = s.header.span[:lvl]
 	}
 	s.length--
 
 	return candidate.value, true
 }
 
-// NewCustomMap returns a new SkipList that will use lessThan as the
-// comparison function. lessThan should define a linear order on keys
-// you intend to use with the SkipList.
-func NewCustomMap(lessThan func(l, r interface{}) bool) *SkipList {
-	return &SkipList{
-		lessThan: lessThan,
-		header: &node{
-			forward: []*node{nil},
-		},
-		update: make([]*node, DefaultMaxLevel+1),
+// NewOrdered returns an empty SkipList keyed by any type with a natural
+// total order, as defined by constraints.Ordered.
+func NewOrdered[K constraints.Ordered, V any]() *SkipList[K, V] {
+	return NewCustom[K, V](func(a, b K) bool { return a < b })
+}
+
+// NewCustom returns a new SkipList that will use less as the comparison
+// function. less should define a linear order on keys you intend to use
+// with the SkipList.
+func NewCustom[K, V any](less func(a, b K) bool) *SkipList[K, V] {
+	return NewWithOptions[K, V](less, SkipListOptions{})
+}
+
+// Map is the original interface{}-keyed skip list API. It is kept for
+// callers that have not migrated to the generic SkipList[K, V] and is a
+// thin wrapper around SkipList[interface{}, interface{}].
+type Map struct {
+	inner *SkipList[interface{}, interface{}]
+}
+
+// Len returns the length of m.
+func (m *Map) Len() int {
+	return m.inner.Len()
+}
+
+// Get returns the value associated with key from m (nil if the key is
+// not present in m). The second return value is true when the key is
+// present.
+func (m *Map) Get(key interface{}) (value interface{}, ok bool) {
+	return m.inner.Get(key)
+}
+
+// GetGreaterOrEqual finds the node whose key is greater than or equal
+// to min. It returns its value, its actual key, and whether such a
+// node is present in the skip list.
+func (m *Map) GetGreaterOrEqual(min interface{}) (actualKey, value interface{}, ok bool) {
+	return m.inner.GetGreaterOrEqual(min)
+}
+
+// Set sets the value associated with key in m. As with the original
+// interface{}-keyed API, a nil key is silently ignored rather than
+// passed down to the generic SkipList, which cannot compare a nil
+// interface{} against real keys.
+func (m *Map) Set(key, value interface{}) {
+	if key == nil {
+		return
 	}
+	m.inner.Set(key, value)
+}
+
+// Delete removes the node with the given key. As with the original
+// interface{}-keyed API, a nil key is silently ignored.
+//
+// It returns the old value and whether the node was present.
+func (m *Map) Delete(key interface{}) (value interface{}, ok bool) {
+	if key == nil {
+		return nil, false
+	}
+	return m.inner.Delete(key)
+}
+
+// Iterator returns an Iterator that will go through all elements of m.
+func (m *Map) Iterator() Iterator[interface{}, interface{}] {
+	return m.inner.Iterator()
+}
+
+// Seek returns a bidirectional iterator starting with the first element whose
+// key is greater or equal to key; otherwise, a nil iterator is returned.
+func (m *Map) Seek(key interface{}) Iterator[interface{}, interface{}] {
+	return m.inner.Seek(key)
 }
 
-// NewIntKey returns a SkipList that accepts int keys.
-func NewIntMap() *SkipList {
+// SeekToFirst returns a bidirectional iterator starting from the first element
+// in the list if the list is populated; otherwise, a nil iterator is returned.
+func (m *Map) SeekToFirst() Iterator[interface{}, interface{}] {
+	return m.inner.SeekToFirst()
+}
+
+// SeekToLast returns a bidirectional iterator starting from the last element
+// in the list if the list is populated; otherwise, a nil iterator is returned.
+func (m *Map) SeekToLast() Iterator[interface{}, interface{}] {
+	return m.inner.SeekToLast()
+}
+
+// Range returns an iterator that will go through all the
+// elements of m that are greater or equal than from, but less than to.
+func (m *Map) Range(from, to interface{}) Iterator[interface{}, interface{}] {
+	return m.inner.Range(from, to)
+}
+
+// NewCustomMap returns a new Map that will use lessThan as the
+// comparison function. lessThan should define a linear order on keys
+// you intend to use with the Map.
+func NewCustomMap(lessThan func(l, r interface{}) bool) *Map {
+	return &Map{inner: NewCustom[interface{}, interface{}](lessThan)}
+}
+
+// NewIntMap returns a Map that accepts int keys.
+func NewIntMap() *Map {
 	return NewCustomMap(func(l, r interface{}) bool {
 		return l.(int) < r.(int)
 	})
 }
 
-// NewIntKey returns a SkipList that accepts int keys.
-func NewInt32Map() *SkipList {
+// NewInt32Map returns a Map that accepts int32 keys.
+func NewInt32Map() *Map {
 	return NewCustomMap(func(l, r interface{}) bool {
 		return l.(int32) < r.(int32)
 	})
 }
 
-// NewStringMap returns a SkipList that accepts string keys.
-func NewStringMap() *SkipList {
+// NewStringMap returns a Map that accepts string keys.
+func NewStringMap() *Map {
 	return NewCustomMap(func(l, r interface{}) bool {
 		return l.(string) < r.(string)
 	})
-}
\ No newline at end of file
+}
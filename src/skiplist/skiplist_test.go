@@ -0,0 +1,166 @@
+package skiplist
+
+import (
+	"testing"
+)
+
+func TestSetGetDelete(t *testing.T) {
+	s := NewOrdered[int, string]()
+
+	if _, ok := s.Get(1); ok {
+		t.Fatal("Get on an empty list returned ok = true")
+	}
+
+	s.Set(1, "one")
+	s.Set(2, "two")
+	s.Set(1, "ONE") // overwrite
+
+	if v, ok := s.Get(1); !ok || v != "ONE" {
+		t.Errorf("Get(1) = (%q, %v), want (\"ONE\", true)", v, ok)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", s.Len())
+	}
+
+	if v, ok := s.Delete(1); !ok || v != "ONE" {
+		t.Errorf("Delete(1) = (%q, %v), want (\"ONE\", true)", v, ok)
+	}
+	if _, ok := s.Get(1); ok {
+		t.Error("Get(1) after Delete(1) returned ok = true")
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d after one Delete, want 1", s.Len())
+	}
+
+	if _, ok := s.Delete(100); ok {
+		t.Error("Delete on an absent key returned ok = true")
+	}
+}
+
+func TestGetGreaterOrEqual(t *testing.T) {
+	s := NewOrdered[int, string]()
+	s.Set(10, "ten")
+	s.Set(20, "twenty")
+	s.Set(30, "thirty")
+
+	key, value, ok := s.GetGreaterOrEqual(15)
+	if !ok || key != 20 || value != "twenty" {
+		t.Errorf("GetGreaterOrEqual(15) = (%d, %q, %v), want (20, \"twenty\", true)", key, value, ok)
+	}
+
+	if _, _, ok := s.GetGreaterOrEqual(31); ok {
+		t.Error("GetGreaterOrEqual(31) = ok, want false when nothing is >= 31")
+	}
+
+	key, value, ok = s.GetGreaterOrEqual(20)
+	if !ok || key != 20 || value != "twenty" {
+		t.Errorf("GetGreaterOrEqual(20) = (%d, %q, %v), want (20, \"twenty\", true) for an exact match", key, value, ok)
+	}
+}
+
+func TestIteratorWalksInOrder(t *testing.T) {
+	s := NewOrdered[int, int]()
+	want := []int{5, 1, 4, 2, 3}
+	for _, k := range want {
+		s.Set(k, k*100)
+	}
+
+	it := s.Iterator()
+	var got []int
+	for it.Next() {
+		got = append(got, it.Key())
+		if it.Value() != it.Key()*100 {
+			t.Fatalf("Value() = %d at Key() = %d, want %d", it.Value(), it.Key(), it.Key()*100)
+		}
+	}
+	wantSorted := []int{1, 2, 3, 4, 5}
+	if len(got) != len(wantSorted) {
+		t.Fatalf("iterated %d elements, want %d", len(got), len(wantSorted))
+	}
+	for i, k := range wantSorted {
+		if got[i] != k {
+			t.Errorf("element %d = %d, want %d", i, got[i], k)
+		}
+	}
+}
+
+func TestSeekAndPrevious(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 5; i++ {
+		s.Set(i, i)
+	}
+
+	it := s.Seek(2)
+	if it == nil {
+		t.Fatal("Seek(2) = nil")
+	}
+	if it.Key() != 2 {
+		t.Fatalf("Seek(2).Key() = %d, want 2", it.Key())
+	}
+	if !it.Previous() || it.Key() != 1 {
+		t.Fatalf("Previous() after Seek(2) landed on %d, want 1", it.Key())
+	}
+	if !it.Next() || it.Key() != 2 {
+		t.Fatalf("Next() after Previous() landed on %d, want 2", it.Key())
+	}
+
+	if it := s.Seek(100); it != nil {
+		t.Error("Seek(100) on a list with no key >= 100 should return nil")
+	}
+}
+
+func TestSeekToFirstAndLast(t *testing.T) {
+	s := NewOrdered[int, int]()
+	if s.SeekToFirst() != nil {
+		t.Error("SeekToFirst() on an empty list should return nil")
+	}
+	if s.SeekToLast() != nil {
+		t.Error("SeekToLast() on an empty list should return nil")
+	}
+
+	s.Set(3, 3)
+	s.Set(1, 1)
+	s.Set(2, 2)
+
+	if it := s.SeekToFirst(); it == nil || it.Key() != 1 {
+		t.Errorf("SeekToFirst().Key() = %v, want 1", it)
+	}
+	if it := s.SeekToLast(); it == nil || it.Key() != 3 {
+		t.Errorf("SeekToLast().Key() = %v, want 3", it)
+	}
+}
+
+func TestIteratorClone(t *testing.T) {
+	s := NewOrdered[int, int]()
+	for i := 0; i < 3; i++ {
+		s.Set(i, i)
+	}
+
+	it := s.SeekToFirst()
+	clone := it.Clone()
+
+	it.Next()
+	if clone.Key() != 0 {
+		t.Errorf("clone.Key() = %d after advancing the original, want 0 (unaffected)", clone.Key())
+	}
+	if it.Key() != 1 {
+		t.Errorf("it.Key() = %d after Next(), want 1", it.Key())
+	}
+}
+
+func TestMapNilKeyIsIgnored(t *testing.T) {
+	m := NewIntMap()
+	m.Set(1, "one")
+
+	m.Set(nil, "ignored")
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d after Set(nil, ...), want 1 (nil key should be ignored)", m.Len())
+	}
+
+	if _, ok := m.Delete(nil); ok {
+		t.Error("Delete(nil) returned ok = true, want false")
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d after Delete(nil), want 1", m.Len())
+	}
+}